@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+const staticPrefix = "/static/"
+
+// assetTypes fixes the Content-Type per extension rather than taking it
+// from the host's mime database, which varies between systems.
+var assetTypes = map[string]string{
+	".css":   "text/css; charset=utf-8",
+	".js":    "text/javascript; charset=utf-8",
+	".json":  "application/json",
+	".svg":   "image/svg+xml",
+	".png":   "image/png",
+	".ico":   "image/x-icon",
+	".woff2": "font/woff2",
+}
+
+// assetSet serves the files in fsys under /static/. When hashed is set,
+// each file is served under a name carrying the first 8 hex digits of its
+// SHA-256 (e.g. "viewer.css" becomes "viewer.<hash>.css") and may be
+// cached forever; otherwise files are served under their own names and
+// looked up on every request.
+type assetSet struct {
+	fsys   fs.FS
+	hashed bool
+	names  map[string]string
+	files  map[string]string
+}
+
+func newAssetSet(fsys fs.FS, hashed bool) (*assetSet, error) {
+	s := &assetSet{fsys: fsys, hashed: hashed}
+	if !hashed {
+		return s, nil
+	}
+
+	s.names = make(map[string]string)
+	s.files = make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		served := hashedName(name, data)
+		s.names[name] = served
+		s.files[served] = name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func hashedName(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:4]) + ext
+}
+
+// href returns the URL for a logical asset name. It is exposed to
+// templates as "asset", so a misspelt name fails the render.
+func (s *assetSet) href(name string) (string, error) {
+	if s.hashed {
+		if served, ok := s.names[name]; ok {
+			return staticPrefix + served, nil
+		}
+	} else if s.exists(name) {
+		return staticPrefix + name, nil
+	}
+	return "", fmt.Errorf("unknown static asset %q", name)
+}
+
+// lookup maps a served name back to the file it refers to.
+func (s *assetSet) lookup(served string) (string, bool) {
+	if s.hashed {
+		name, ok := s.files[served]
+		return name, ok
+	}
+	return served, s.exists(served)
+}
+
+func (s *assetSet) exists(name string) bool {
+	if !fs.ValidPath(name) {
+		return false
+	}
+	info, err := fs.Stat(s.fsys, name)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func (s *assetSet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.lookup(strings.TrimPrefix(r.URL.Path, staticPrefix))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	contentType, ok := assetTypes[path.Ext(name)]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if s.hashed {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+}
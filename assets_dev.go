@@ -0,0 +1,13 @@
+//go:build devassets
+
+package main
+
+import "os"
+
+// loadAssets serves ./static, relative to the working directory, straight
+// from disk: every request looks the file up again, so edited, added and
+// renamed files show up without a restart. Names are left unhashed and
+// responses are sent with Cache-Control: no-cache.
+func loadAssets() (*assetSet, error) {
+	return newAssetSet(os.DirFS("static"), false)
+}
@@ -0,0 +1,21 @@
+//go:build !devassets
+
+package main
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed static
+var embeddedStatic embed.FS
+
+// loadAssets serves the bundle compiled into the binary under hashed,
+// long-lived filenames. Build with -tags devassets to read ./static instead.
+func loadAssets() (*assetSet, error) {
+	sub, err := fs.Sub(embeddedStatic, "static")
+	if err != nil {
+		return nil, err
+	}
+	return newAssetSet(sub, true)
+}
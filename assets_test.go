@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+var testStatic = fstest.MapFS{
+	"viewer.css": {Data: []byte("iframe{border:none}\n")},
+	"app.js":     {Data: []byte("void 0;\n")},
+}
+
+// checkGolden compares got against testdata/<test name>.golden, rewriting
+// the file first when -update is set.
+func checkGolden(t *testing.T, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", filepath.FromSlash(t.Name())+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+// dumpHeaders renders the status line and headers of rec, sorted by name.
+func dumpHeaders(rec *httptest.ResponseRecorder) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d %s\n", rec.Code, http.StatusText(rec.Code))
+	names := make([]string, 0, len(rec.Header()))
+	for name := range rec.Header() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range rec.Header()[name] {
+			fmt.Fprintf(&buf, "%s: %s\n", name, v)
+		}
+	}
+	return buf.Bytes()
+}
+
+func mustHref(t *testing.T, s *assetSet, name string) string {
+	t.Helper()
+	href, err := s.href(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return href
+}
+
+type assetCase struct {
+	name   string
+	target string
+}
+
+// serveAssetCases serves each case from s and compares the status line and
+// headers against testdata/<test>/<case>.golden.
+func serveAssetCases(t *testing.T, s *assetSet, cases []assetCase) {
+	t.Helper()
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			checkGolden(t, dumpHeaders(rec))
+		})
+	}
+}
+
+func TestAssetSetHashed(t *testing.T) {
+	s, err := newAssetSet(testStatic, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveAssetCases(t, s, []assetCase{
+		{"hashed_css", mustHref(t, s, "viewer.css")},
+		{"hashed_js", mustHref(t, s, "app.js")},
+		{"unhashed_name", "/static/viewer.css"},
+		{"prefix_only", "/static/"},
+		{"unknown", "/static/nope"},
+		{"traversal", "/static/../main.go"},
+	})
+}
+
+func TestAssetSetUnhashed(t *testing.T) {
+	s, err := newAssetSet(testStatic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveAssetCases(t, s, []assetCase{
+		{"plain_css", "/static/viewer.css"},
+		{"prefix_only", "/static/"},
+		{"unknown", "/static/nope"},
+		{"traversal", "/static/../main.go"},
+	})
+}
+
+func TestAssetHrefUnknown(t *testing.T) {
+	for _, hashed := range []bool{true, false} {
+		s, err := newAssetSet(testStatic, hashed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if href, err := s.href("missing.css"); err == nil {
+			t.Errorf("hashed=%v: href(missing.css) = %q, want error", hashed, href)
+		}
+	}
+}
+
+func TestIframePageGolden(t *testing.T) {
+	s, err := newAssetSet(testStatic, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := newIframePage(s).Execute(&buf, "/raw?addr=web://[abc]:4433/index.html"); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, buf.Bytes())
+}
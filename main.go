@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"html"
+	"html/template"
 	"log"
 	"net/http"
 	"os"
@@ -11,16 +14,41 @@ import (
 	"github.com/usenwep/nwfetch-go"
 )
 
+const (
+	// nwfetch times the handshake and the response separately, so a
+	// cold, dead address costs up to connectTimeout before the response
+	// timeout even starts.
+	connectTimeout  = 3 * time.Second
+	responseTimeout = 3 * time.Second
+
+	// upstreamTimeout bounds how long /raw waits on the upstream fetch.
+	// nwfetch has been seen to hang well past WithTimeout (see UPSTREAM.md,
+	// synth-112), so the handler enforces the combined budget itself.
+	upstreamTimeout = connectTimeout + responseTimeout
+)
+
 var client *nwfetch.Client
 
+var iframePage *template.Template
+
 func main() {
 	var err error
-	client, err = nwfetch.NewClient(nwfetch.WithTimeout(3 * time.Second))
+	client, err = nwfetch.NewClient(
+		nwfetch.WithConnectTimeout(connectTimeout),
+		nwfetch.WithTimeout(responseTimeout),
+	)
 	if err != nil {
 		log.Fatalf("failed to create nwfetch client: %v", err)
 	}
 	defer client.Close()
 
+	assets, err := loadAssets()
+	if err != nil {
+		log.Fatalf("failed to load static assets: %v", err)
+	}
+	iframePage = newIframePage(assets)
+
+	http.Handle(staticPrefix, assets)
 	http.HandleFunc("/raw", handleRaw)
 	http.HandleFunc("/", handleIframe)
 
@@ -40,7 +68,15 @@ func handleRaw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := client.Get(target)
+	ctx, cancel := context.WithTimeout(r.Context(), upstreamTimeout)
+	defer cancel()
+
+	resp, err := getWithin(ctx, client.Get, target)
+	if errors.Is(err, context.DeadlineExceeded) {
+		w.WriteHeader(http.StatusGatewayTimeout)
+		fmt.Fprintf(w, "Timed out reaching %s", target)
+		return
+	}
 	if err != nil {
 		fmt.Fprintf(w, "Unable to reach %s", target)
 		return
@@ -60,6 +96,41 @@ func handleRaw(w http.ResponseWriter, r *http.Request) {
 	w.Write(resp.Body)
 }
 
+// getWithin runs get in the background and returns its result, or
+// ctx.Err() if ctx ends first. nwfetch's Get takes no context, so an
+// abandoned fetch keeps running until the library gives up on it; its
+// result is then discarded.
+func getWithin(ctx context.Context, get func(string) (*nwfetch.Response, error), target string) (*nwfetch.Response, error) {
+	type result struct {
+		resp *nwfetch.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := get(target)
+		done <- result{resp, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.resp, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newIframePage(assets *assetSet) *template.Template {
+	return template.Must(template.New("iframe").Funcs(template.FuncMap{
+		"asset": assets.href,
+	}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>HTTP to NWEP Proxy Server</title>
+<link rel="stylesheet" href="{{asset "viewer.css"}}">
+</head>
+<body><iframe src="{{.}}"></iframe></body>
+</html>`))
+}
+
 func handleIframe(w http.ResponseWriter, r *http.Request) {
 	target := r.URL.Query().Get("addr")
 	if target == "" {
@@ -67,13 +138,13 @@ func handleIframe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	escaped := html.EscapeString("/raw?addr=" + target)
+	var buf bytes.Buffer
+	if err := iframePage.Execute(&buf, "/raw?addr="+target); err != nil {
+		log.Printf("render iframe page: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	fmt.Fprintf(w, `<!DOCTYPE html>
-<html>
-<head><meta charset="utf-8"><title>HTTP to NWEP Proxy Server</title>
-<style>*{margin:0;padding:0}iframe{width:100%%;height:100vh;border:none}</style>
-</head>
-<body><iframe src="%s"></iframe></body>
-</html>`, escaped)
+	w.Write(buf.Bytes())
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/usenwep/nwfetch-go"
+)
+
+func TestGetWithin(t *testing.T) {
+	fast := func(string) (*nwfetch.Response, error) {
+		return &nwfetch.Response{Status: "ok"}, nil
+	}
+	failing := func(string) (*nwfetch.Response, error) {
+		return nil, errors.New("connect refused")
+	}
+	release := make(chan struct{})
+	defer close(release)
+	hanging := func(string) (*nwfetch.Response, error) {
+		<-release
+		return &nwfetch.Response{Status: "ok"}, nil
+	}
+
+	tests := []struct {
+		name    string
+		get     func(string) (*nwfetch.Response, error)
+		wantErr error
+		wantOK  bool
+	}{
+		{"fast", fast, nil, true},
+		{"failing", failing, nil, false},
+		{"hanging", hanging, context.DeadlineExceeded, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			resp, err := getWithin(ctx, tt.get, "web://[abc]:4433/")
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("getWithin took %v, want it bounded by the context", elapsed)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantOK != (err == nil && resp != nil) {
+				t.Fatalf("resp = %v, err = %v, want ok=%v", resp, err, tt.wantOK)
+			}
+		})
+	}
+}